@@ -0,0 +1,148 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Container holds a set of WebServices and dispatches incoming requests to
+// the best-matching Route among them, the way a single http.Handler would
+// for an entire API.
+type Container struct {
+	webServices   []*WebService
+	pathProcessor PathProcessor
+	corsPolicy    *CORSPolicy
+}
+
+// NewContainer creates an empty Container using the default "{name}"
+// PathProcessor.
+func NewContainer() *Container {
+	return &Container{pathProcessor: defaultPathProcessor}
+}
+
+// SetPathProcessor overrides the PathProcessor every subsequently Add-ed
+// WebService's Routes are built with, e.g. CurlyPathProcessor for its
+// "{name:pattern}" and "{name:*}" support.
+func (c *Container) SetPathProcessor(p PathProcessor) {
+	c.pathProcessor = p
+}
+
+// SetCORSPolicy installs a container-wide default CORSPolicy, applied to
+// every subsequently Add-ed Route that doesn't set its own Route.CORS. A
+// route's own policy always takes precedence over this default, both for
+// OPTIONS preflight and for the headers an actual invocation gets.
+func (c *Container) SetCORSPolicy(policy *CORSPolicy) {
+	c.corsPolicy = policy
+}
+
+// Add registers ws, assigning the Container's PathProcessor to each of its
+// Routes, building them, and falling back to the container's default
+// CORSPolicy (if any) for routes that don't declare their own.
+func (c *Container) Add(ws *WebService) *Container {
+	for i := range ws.routes {
+		route := &ws.routes[i]
+		route.processor = c.pathProcessor
+		route.postBuild()
+		if route.CORS == nil && c.corsPolicy != nil {
+			route.Filters = append([]FilterFunction{c.corsPolicy.filter}, route.Filters...)
+		}
+	}
+	c.webServices = append(c.webServices, ws)
+	return c
+}
+
+// RegisteredWebServices returns every WebService added to the Container, in
+// the order they were added.
+func (c *Container) RegisteredWebServices() []*WebService {
+	return c.webServices
+}
+
+// candidateRoutes returns every Route, across every WebService, whose Path
+// and custom verb match urlPath, regardless of HTTP method.
+func (c *Container) candidateRoutes(urlPath string) []*Route {
+	var candidates []*Route
+	for _, ws := range c.webServices {
+		if !strings.HasPrefix(urlPath, ws.rootPath) {
+			continue
+		}
+		for i := range ws.routes {
+			route := &ws.routes[i]
+			if route.matches(urlPath) {
+				candidates = append(candidates, route)
+			}
+		}
+	}
+	return candidates
+}
+
+// ServeHTTP implements http.Handler. It locates every Route whose Path and
+// custom verb match the request (so "/users/{id}" and "/users/{id}:activate"
+// dispatch to distinct routes), short-circuits an OPTIONS preflight through
+// the first matching Route whose own CORSPolicy accepts it (falling back to the
+// Container's default CORSPolicy, if any, when no candidate has one),
+// narrows same-method candidates to those whose Consumes accepts the
+// request's Content-Type (a route with no Consumes declared accepts any
+// body), and otherwise dispatches to whichever candidate scores highest
+// against the Accept header.
+func (c *Container) ServeHTTP(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	candidates := c.candidateRoutes(httpRequest.URL.Path)
+
+	if httpRequest.Method == http.MethodOptions {
+		resp := newResponse(httpWriter)
+		origin := httpRequest.Header.Get("Origin")
+		requestedMethod := httpRequest.Header.Get("Access-Control-Request-Method")
+		requestedHeaders := httpRequest.Header.Get("Access-Control-Request-Headers")
+		hasOwnPolicy := false
+		for _, route := range candidates {
+			if route.CORS == nil {
+				continue
+			}
+			hasOwnPolicy = true
+			if route.handlePreflight(resp, origin, requestedMethod, requestedHeaders) {
+				return
+			}
+		}
+		// Only fall back to the container default when no candidate route
+		// declared its own policy; a route's own (possibly stricter) policy
+		// always has the final say for that route, even when it refuses
+		// this particular origin/method.
+		if !hasOwnPolicy && c.corsPolicy != nil && len(candidates) > 0 &&
+			c.corsPolicy.handlePreflight(resp, origin, requestedMethod, requestedHeaders) {
+			return
+		}
+	}
+
+	var sameMethod []*Route
+	for _, route := range candidates {
+		if route.Method == httpRequest.Method {
+			sameMethod = append(sameMethod, route)
+		}
+	}
+
+	contentTypeHeader := httpRequest.Header.Get(HEADER_ContentType)
+	consuming := sameMethod
+	if contentTypeHeader != "" {
+		consuming = nil
+		for _, route := range sameMethod {
+			if len(route.Consumes) == 0 || route.contentTypeScore(contentTypeHeader) > 0 {
+				consuming = append(consuming, route)
+			}
+		}
+	}
+
+	acceptHeader := httpRequest.Header.Get(HEADER_Accept)
+	if acceptHeader == "" {
+		acceptHeader = "*/*"
+	}
+	best, score := selectBestRoute(consuming, acceptHeader)
+	if best == nil || score <= 0 {
+		http.NotFound(httpWriter, httpRequest)
+		return
+	}
+	wrappedRequest, wrappedResponse := best.wrapRequestResponse(httpWriter, httpRequest)
+	best.dispatchWithFilters(wrappedRequest, wrappedResponse)
+}
@@ -0,0 +1,81 @@
+package restful
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeHTTPRejectsMismatchedContentType(t *testing.T) {
+	container := NewContainer()
+	ws := NewWebService("/items")
+	ws.AddRoute(Route{
+		Method:   "POST",
+		Path:     "/items",
+		Consumes: []string{"application/xml"},
+		Produces: []string{"application/json"},
+		Function: func(req *Request, resp *Response) {
+			t.Fatal("a JSON body must not dispatch to a route that only Consumes XML")
+		},
+	})
+	container.Add(ws)
+
+	req := httptest.NewRequest("POST", "/items", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	recorder := httptest.NewRecorder()
+
+	container.ServeHTTP(recorder, req)
+
+	if recorder.Code != 404 {
+		t.Errorf("status = %d, want 404", recorder.Code)
+	}
+}
+
+func TestServeHTTPAcceptsDeclaredContentType(t *testing.T) {
+	container := NewContainer()
+	ws := NewWebService("/items")
+	var handlerCalled bool
+	ws.AddRoute(Route{
+		Method:   "POST",
+		Path:     "/items",
+		Consumes: []string{"application/xml"},
+		Produces: []string{"application/json"},
+		Function: func(req *Request, resp *Response) { handlerCalled = true },
+	})
+	container.Add(ws)
+
+	req := httptest.NewRequest("POST", "/items", nil)
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/json")
+	recorder := httptest.NewRecorder()
+
+	container.ServeHTTP(recorder, req)
+
+	if !handlerCalled {
+		t.Fatalf("expected the matching Consumes type to dispatch, got status %d", recorder.Code)
+	}
+}
+
+func TestServeHTTPIgnoresConsumesWhenRouteDeclaresNone(t *testing.T) {
+	container := NewContainer()
+	ws := NewWebService("/items")
+	var handlerCalled bool
+	ws.AddRoute(Route{
+		Method:   "GET",
+		Path:     "/items",
+		Produces: []string{"application/json"},
+		Function: func(req *Request, resp *Response) { handlerCalled = true },
+	})
+	container.Add(ws)
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	recorder := httptest.NewRecorder()
+
+	container.ServeHTTP(recorder, req)
+
+	if !handlerCalled {
+		t.Fatalf("a route with no Consumes should accept any (or no) Content-Type, got status %d", recorder.Code)
+	}
+}
@@ -0,0 +1,113 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSPolicy configures Cross-Origin Resource Sharing, either as a
+// Container-wide default (Container.SetCORSPolicy) or set directly on a
+// Route, which then overrides the container-level default for that route
+// only. Set it on a Route to expose stricter (or looser) origin rules than
+// the rest of the API, e.g. locking down a mutating endpoint while leaving
+// public GETs permissive.
+type CORSPolicy struct {
+	AllowedOrigins   []string // "*" allows any origin
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int // seconds a preflight response may be cached; 0 omits the header
+}
+
+// allowedOrigin returns the Access-Control-Allow-Origin value for origin, or
+// "" if origin is not permitted by this policy. Per the Fetch/CORS spec,
+// "Access-Control-Allow-Origin: *" combined with
+// "Access-Control-Allow-Credentials: true" is invalid and browsers discard
+// the response, so a "*" entry echoes the concrete origin instead of the
+// literal wildcard whenever AllowCredentials is set.
+func (c *CORSPolicy) allowedOrigin(origin string) string {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			if c.AllowCredentials {
+				return origin
+			}
+			return "*"
+		}
+		if allowed == origin {
+			return origin
+		}
+	}
+	return ""
+}
+
+// handlePreflight answers an OPTIONS preflight request per this policy,
+// writing the Access-Control-* response headers. It returns false (writing
+// nothing) when origin or the requested method is not allowed, in which
+// case the caller should fall back to its own handling.
+func (c *CORSPolicy) handlePreflight(resp *Response, origin, requestedMethod, requestedHeaders string) bool {
+	allowOrigin := c.allowedOrigin(origin)
+	if allowOrigin == "" || !contains(c.AllowedMethods, requestedMethod) {
+		return false
+	}
+	header := resp.Header()
+	header.Set("Access-Control-Allow-Origin", allowOrigin)
+	header.Set("Access-Control-Allow-Methods", strings.Join(c.AllowedMethods, ","))
+	if len(c.AllowedHeaders) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ","))
+	} else if requestedHeaders != "" {
+		header.Set("Access-Control-Allow-Headers", requestedHeaders)
+	}
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if c.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+	}
+	resp.WriteHeader(http.StatusOK)
+	return true
+}
+
+// filter is prepended to the Route's Filters by postBuild when CORS is set,
+// so every actual (non-preflight) invocation of the route gets the
+// Access-Control-* response headers its policy declares.
+func (c *CORSPolicy) filter(req *Request, resp *Response, chain *FilterChain) {
+	origin := req.Request.Header.Get("Origin")
+	if allowOrigin := c.allowedOrigin(origin); allowOrigin != "" {
+		header := resp.Header()
+		header.Set("Access-Control-Allow-Origin", allowOrigin)
+		if len(c.ExposedHeaders) > 0 {
+			header.Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ","))
+		}
+		if c.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+	chain.ProcessFilter(req, resp)
+}
+
+// handlePreflight reports whether this route has its own CORSPolicy able to
+// answer an OPTIONS preflight for origin/requestedMethod; if so it writes
+// the response headers and returns true. The container consults this before
+// falling back to its container-level CORSPolicy, so a route's own policy
+// takes precedence over the container default.
+func (r Route) handlePreflight(resp *Response, origin, requestedMethod, requestedHeaders string) bool {
+	if r.CORS == nil {
+		return false
+	}
+	return r.CORS.handlePreflight(resp, origin, requestedMethod, requestedHeaders)
+}
+
+func contains(list []string, value string) bool {
+	for _, each := range list {
+		if each == value {
+			return true
+		}
+	}
+	return false
+}
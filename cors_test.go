@@ -0,0 +1,166 @@
+package restful
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSPreflightNegotiatesHeaders(t *testing.T) {
+	policy := &CORSPolicy{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"POST"},
+		MaxAge:         600,
+	}
+	resp := newResponse(httptest.NewRecorder())
+
+	handled := policy.handlePreflight(resp, "https://example.com", "POST", "X-Custom")
+	if !handled {
+		t.Fatal("expected preflight to be handled")
+	}
+	if got := resp.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := resp.Header().Get("Access-Control-Allow-Methods"); got != "POST" {
+		t.Errorf("Allow-Methods = %q", got)
+	}
+	if got := resp.Header().Get("Access-Control-Allow-Headers"); got != "X-Custom" {
+		t.Errorf("Allow-Headers = %q", got)
+	}
+	if got := resp.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Max-Age = %q", got)
+	}
+}
+
+func TestCORSPreflightRejectsDisallowedOriginOrMethod(t *testing.T) {
+	policy := &CORSPolicy{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"POST"},
+	}
+	resp := newResponse(httptest.NewRecorder())
+	if policy.handlePreflight(resp, "https://evil.example", "POST", "") {
+		t.Error("should refuse an origin not on the allow list")
+	}
+	if policy.handlePreflight(resp, "https://example.com", "DELETE", "") {
+		t.Error("should refuse a method not on the allow list")
+	}
+}
+
+func TestCORSWildcardWithCredentialsEchoesOrigin(t *testing.T) {
+	policy := &CORSPolicy{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	}
+	got := policy.allowedOrigin("https://example.com")
+	if got != "https://example.com" {
+		t.Errorf("allowedOrigin = %q, want the literal request origin, not \"*\", because AllowCredentials is set", got)
+	}
+}
+
+func TestCORSWildcardWithoutCredentialsStaysLiteral(t *testing.T) {
+	policy := &CORSPolicy{AllowedOrigins: []string{"*"}}
+	if got := policy.allowedOrigin("https://example.com"); got != "*" {
+		t.Errorf("allowedOrigin = %q, want literal \"*\"", got)
+	}
+}
+
+func TestContainerFallsBackToDefaultCORSPolicy(t *testing.T) {
+	container := NewContainer()
+	container.SetCORSPolicy(&CORSPolicy{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET"},
+	})
+	ws := NewWebService("/widgets")
+	var handlerCalled bool
+	ws.AddRoute(Route{
+		Method:   "GET",
+		Path:     "/widgets",
+		Produces: []string{"application/json"},
+		Function: func(req *Request, resp *Response) {
+			handlerCalled = true
+		},
+	})
+	container.Add(ws)
+
+	preflight := httptest.NewRequest("OPTIONS", "/widgets", nil)
+	preflight.Header.Set("Origin", "https://example.com")
+	preflight.Header.Set("Access-Control-Request-Method", "GET")
+	preflightRecorder := httptest.NewRecorder()
+	container.ServeHTTP(preflightRecorder, preflight)
+
+	if got := preflightRecorder.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("preflight Allow-Origin = %q, want the container's default policy to answer", got)
+	}
+	if handlerCalled {
+		t.Fatal("OPTIONS preflight must not invoke the route's Function")
+	}
+
+	actual := httptest.NewRequest("GET", "/widgets", nil)
+	actual.Header.Set("Origin", "https://example.com")
+	actual.Header.Set("Accept", "application/json")
+	actualRecorder := httptest.NewRecorder()
+	container.ServeHTTP(actualRecorder, actual)
+
+	if !handlerCalled {
+		t.Fatal("expected the actual GET to dispatch")
+	}
+	if got := actualRecorder.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("actual-invocation Allow-Origin = %q, want the container's default policy applied", got)
+	}
+}
+
+func TestRouteCORSOverridesContainerDefault(t *testing.T) {
+	container := NewContainer()
+	container.SetCORSPolicy(&CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"POST"},
+	})
+	ws := NewWebService("/widgets")
+	ws.AddRoute(Route{
+		Method: "POST",
+		Path:   "/widgets",
+		CORS: &CORSPolicy{
+			AllowedOrigins: []string{"https://trusted.example"},
+			AllowedMethods: []string{"POST"},
+		},
+		Function: func(req *Request, resp *Response) {},
+	})
+	container.Add(ws)
+
+	req := httptest.NewRequest("OPTIONS", "/widgets", nil)
+	req.Header.Set("Origin", "https://untrusted.example")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+
+	if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Allow-Origin = %q, want the route's own (stricter) policy to reject the untrusted origin rather than falling back to the container default", got)
+	}
+}
+
+func TestContainerShortCircuitsOptionsPreflightViaRoutePolicy(t *testing.T) {
+	container := NewContainer()
+	ws := NewWebService("/widgets")
+	ws.AddRoute(Route{
+		Method: "POST",
+		Path:   "/widgets",
+		CORS: &CORSPolicy{
+			AllowedOrigins: []string{"https://example.com"},
+			AllowedMethods: []string{"POST"},
+		},
+		Function: func(req *Request, resp *Response) {
+			t.Fatal("OPTIONS preflight must not invoke the route's Function")
+		},
+	})
+	container.Add(ws)
+
+	req := httptest.NewRequest("OPTIONS", "/widgets", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	recorder := httptest.NewRecorder()
+
+	container.ServeHTTP(recorder, req)
+
+	if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+}
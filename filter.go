@@ -0,0 +1,31 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// FilterFunction defines the function signature for a filter. A filter
+// wraps a Route's Function (or the next filter in the chain) so it can
+// inspect or modify the request and response before and after the handler
+// runs, e.g. CORS header injection or request-body validation.
+type FilterFunction func(*Request, *Response, *FilterChain)
+
+// FilterChain manages the sequential execution of Filters, finally
+// invoking Target once every Filter has called chain.ProcessFilter.
+type FilterChain struct {
+	Filters []FilterFunction
+	Target  RouteFunction
+	index   int
+}
+
+// ProcessFilter invokes the next Filter in the chain, or Target once none
+// remain.
+func (c *FilterChain) ProcessFilter(req *Request, resp *Response) {
+	if c.index >= len(c.Filters) {
+		c.Target(req, resp)
+		return
+	}
+	filter := c.Filters[c.index]
+	c.index++
+	filter(req, resp, c)
+}
@@ -0,0 +1,28 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// selectBestRoute picks, among a set of routes whose path already matched
+// the request, the one whose Produces list scores highest against the
+// client's Accept header (RFC 7231 §5.3 quality values). Ties are broken in
+// favor of the first route in declaration order. It returns a nil Route and
+// a score of 0 when none of the candidates can produce anything the client
+// accepts.
+//
+// This is used by the container during dispatch instead of returning the
+// first route whose Produces happens to contain a match, so that a client
+// sending e.g. "application/xml;q=0.9, application/json;q=0.1" is routed to
+// the XML producer even when JSON is declared first.
+func selectBestRoute(routes []*Route, acceptHeader string) (*Route, float64) {
+	var best *Route
+	bestScore := 0.0
+	for _, route := range routes {
+		if score := route.acceptScore(acceptHeader); score > bestScore {
+			best = route
+			bestScore = score
+		}
+	}
+	return best, bestScore
+}
@@ -0,0 +1,77 @@
+// Package openapi generates an OpenAPI 3.0 document from the routes and
+// documentation already attached to a restful.Container, so that services
+// built with this package can expose a machine-readable spec without
+// hand-writing one.
+package openapi
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// Document is the root of an OpenAPI 3.0 document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components,omitempty"`
+}
+
+// Info carries the required OpenAPI info object.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem groups the operations available on a single path.
+type PathItem map[string]Operation
+
+// Operation describes a single Method+Path combination.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter documents a single path, query, header or form parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody documents the payload accepted by an operation.
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+// Response documents a single response, keyed by status code in Operation.Responses.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType pairs a Consumes/Produces mime type with the schema of its body.
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// Components holds the schemas referenced by $ref from operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is a (deliberately partial) JSON Schema, reflected from Go structs
+// by reflectSchema. Only the keywords this package emits are modeled.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
@@ -0,0 +1,116 @@
+package openapi
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"regexp"
+	"strings"
+
+	restful "github.com/strogo/go-restful"
+)
+
+// BuildDocument walks every WebService registered on container and produces
+// an OpenAPI 3.0 Document describing their routes. Route.ReadSample and
+// Route.WriteSample are reflected into components/schemas; Route.Doc and
+// Route.Operation become the operation's summary and operationId.
+func BuildDocument(container *restful.Container, title, version string) *Document {
+	builder := &schemaBuilder{components: map[string]*Schema{}}
+	doc := &Document{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]PathItem{},
+	}
+	for _, ws := range container.RegisteredWebServices() {
+		for _, route := range ws.Routes() {
+			path := toOpenAPIPath(route.Path)
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = PathItem{}
+				doc.Paths[path] = item
+			}
+			item[strings.ToLower(route.Method)] = buildOperation(builder, route)
+		}
+	}
+	doc.Components = Components{Schemas: builder.components}
+	return doc
+}
+
+// buildOperation maps a single restful.Route onto an OpenAPI Operation.
+func buildOperation(builder *schemaBuilder, route restful.Route) Operation {
+	op := Operation{
+		OperationID: route.Operation,
+		Summary:     route.Doc,
+		Responses:   map[string]Response{"200": {Description: "OK"}},
+	}
+	for _, p := range route.ParameterDocs {
+		data := p.Data()
+		in := parameterKindToIn(data.Kind)
+		if in == "" { // body parameters are carried via RequestBody, not Parameters
+			continue
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        data.Name,
+			In:          in,
+			Description: data.Description,
+			Required:    data.Required,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+	if route.ReadSample != nil {
+		content := map[string]MediaType{}
+		schema := builder.schemaFor(route.ReadSample)
+		for _, consume := range route.Consumes {
+			content[consume] = MediaType{Schema: *schema}
+		}
+		op.RequestBody = &RequestBody{Content: content}
+	}
+	if route.WriteSample != nil {
+		content := map[string]MediaType{}
+		schema := builder.schemaFor(route.WriteSample)
+		for _, produce := range route.Produces {
+			content[produce] = MediaType{Schema: *schema}
+		}
+		op.Responses["200"] = Response{Description: "OK", Content: content}
+	}
+	return op
+}
+
+func parameterKindToIn(kind int) string {
+	switch kind {
+	case restful.PathParameterKind:
+		return "path"
+	case restful.QueryParameterKind:
+		return "query"
+	case restful.HeaderParameterKind:
+		return "header"
+	default:
+		return ""
+	}
+}
+
+// curlyConstraint matches a CurlyPathProcessor "{name:pattern}" or
+// "{name:*}" segment, capturing the parameter name.
+var curlyConstraint = regexp.MustCompile(`\{([^:}]+):[^}]*\}`)
+
+// toOpenAPIPath rewrites go-restful's path parameter syntax into the plain
+// "{name}" form OpenAPI's path templating expects. A CurlyPathProcessor
+// route's "{name:pattern}" regex constraint or "{name:*}" tail wildcard has
+// no OpenAPI equivalent, so the ":constraint"/"..:*" suffix is stripped;
+// the plain regexPathProcessor "{name}" syntax passes through unchanged.
+func toOpenAPIPath(path string) string {
+	return curlyConstraint.ReplaceAllString(path, "{$1}")
+}
+
+// RouteFunction returns a restful.RouteFunction that serves the OpenAPI
+// document for container as JSON. The document is built once, from the
+// WebServices registered when RouteFunction is called. Mount it directly on
+// a WebService, e.g.
+// ws.AddRoute(restful.Route{Method: "GET", Path: "/openapi.json", Function: openapi.RouteFunction(container, "My API", "1.0")}).
+func RouteFunction(container *restful.Container, title, version string) restful.RouteFunction {
+	doc := BuildDocument(container, title, version)
+	return func(req *restful.Request, resp *restful.Response) {
+		resp.WriteAsJson(doc)
+	}
+}
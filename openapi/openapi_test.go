@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"testing"
+
+	restful "github.com/strogo/go-restful"
+)
+
+func TestToOpenAPIPathStripsCurlyConstraints(t *testing.T) {
+	cases := []struct {
+		path, want string
+	}{
+		{"/items/{id:[0-9]+}", "/items/{id}"},
+		{"/files/{path:*}", "/files/{path}"},
+		{"/items/{id:[0-9]+}/children/{childID:[a-z]+}", "/items/{id}/children/{childID}"},
+		{"/users/{id}", "/users/{id}"},
+	}
+	for _, c := range cases {
+		if got := toOpenAPIPath(c.path); got != c.want {
+			t.Errorf("toOpenAPIPath(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
+
+func TestBuildDocumentStripsCurlyConstraintsFromPathKeys(t *testing.T) {
+	container := restful.NewContainer()
+	container.SetPathProcessor(restful.CurlyPathProcessor)
+	ws := restful.NewWebService("/items")
+	ws.AddRoute(restful.Route{Method: "GET", Path: "/items/{id:[0-9]+}"})
+	container.Add(ws)
+
+	doc := BuildDocument(container, "Test", "1.0")
+
+	if _, ok := doc.Paths["/items/{id}"]; !ok {
+		t.Errorf("expected Paths to key on \"/items/{id}\", got %v", doc.Paths)
+	}
+	if _, ok := doc.Paths["/items/{id:[0-9]+}"]; ok {
+		t.Error("raw CurlyPathProcessor constraint syntax must not leak into an OpenAPI path key")
+	}
+}
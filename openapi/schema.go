@@ -0,0 +1,123 @@
+package openapi
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"reflect"
+	"strings"
+)
+
+// schemaBuilder reflects Go types into Schema values, registering named
+// struct schemas into components as it goes so repeated types are $ref'd
+// instead of inlined.
+type schemaBuilder struct {
+	components map[string]*Schema
+}
+
+// schemaFor returns the $ref-or-inline Schema for sample, registering any
+// struct types it encounters (directly or through fields) into components.
+func (b *schemaBuilder) schemaFor(sample interface{}) *Schema {
+	if sample == nil {
+		return &Schema{}
+	}
+	return b.schemaForType(reflect.TypeOf(sample))
+}
+
+func (b *schemaBuilder) schemaForType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return b.structSchema(t)
+		}
+		if _, ok := b.components[name]; !ok {
+			// reserve the name before recursing so self-referential
+			// structs don't loop forever
+			b.components[name] = &Schema{}
+			b.components[name] = b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaForType(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	default:
+		return &Schema{}
+	}
+}
+
+// structSchema builds an "object" Schema for t, honoring `json:` tags
+// (including "-" and ",omitempty"), embedded (anonymous) structs whose
+// fields are promoted inline, and required fields: a field is required
+// when it is neither a pointer nor tagged omitempty.
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		// An anonymous field's own PkgPath reflects the exportedness of its
+		// *type name*, not of the fields it promotes (e.g. embedding an
+		// unexported "auditFields" still promotes its exported "CreatedBy"),
+		// so it's handled before the unexported-field skip below.
+		if field.Anonymous && tag == "" {
+			embedded := b.structSchema(dereference(field.Type))
+			for k, v := range embedded.Properties {
+				schema.Properties[k] = v
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		if field.PkgPath != "" { // unexported, non-anonymous
+			continue
+		}
+		name, omitempty := parseJSONTag(tag, field.Name)
+		schema.Properties[name] = b.schemaForType(field.Type)
+		if field.Type.Kind() != reflect.Ptr && !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+func parseJSONTag(tag, fieldName string) (name string, omitempty bool) {
+	if tag == "" {
+		return fieldName, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = fieldName
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+func dereference(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
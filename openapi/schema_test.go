@@ -0,0 +1,60 @@
+package openapi
+
+import "testing"
+
+type auditFields struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type widget struct {
+	auditFields
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+func TestStructSchemaPromotesEmbeddedFields(t *testing.T) {
+	builder := &schemaBuilder{components: map[string]*Schema{}}
+	schema := builder.schemaFor(widget{})
+
+	if schema.Ref != "#/components/schemas/widget" {
+		t.Fatalf("schemaFor returned %+v, want a $ref to the registered component", schema)
+	}
+	component, ok := builder.components["widget"]
+	if !ok {
+		t.Fatal("expected a \"widget\" component to be registered")
+	}
+	if _, ok := component.Properties["createdBy"]; !ok {
+		t.Errorf("embedded auditFields.CreatedBy should be promoted to a top-level property, got %+v", component.Properties)
+	}
+	if _, ok := component.Properties["name"]; !ok {
+		t.Errorf("expected a \"name\" property, got %+v", component.Properties)
+	}
+
+	requiredSet := map[string]bool{}
+	for _, name := range component.Required {
+		requiredSet[name] = true
+	}
+	if !requiredSet["createdBy"] {
+		t.Error("promoted embedded field without omitempty should be required")
+	}
+	if !requiredSet["name"] {
+		t.Error("name has no omitempty and should be required")
+	}
+	if requiredSet["tags"] {
+		t.Error("tags is tagged omitempty and should not be required")
+	}
+}
+
+func TestSchemaForTypeHandlesSlicesAndMaps(t *testing.T) {
+	builder := &schemaBuilder{components: map[string]*Schema{}}
+
+	sliceSchema := builder.schemaFor([]string{})
+	if sliceSchema.Type != "array" || sliceSchema.Items == nil || sliceSchema.Items.Type != "string" {
+		t.Errorf("slice schema = %+v", sliceSchema)
+	}
+
+	mapSchema := builder.schemaFor(map[string]int{})
+	if mapSchema.Type != "object" || mapSchema.AdditionalProperties == nil || mapSchema.AdditionalProperties.Type != "integer" {
+		t.Errorf("map schema = %+v", mapSchema)
+	}
+}
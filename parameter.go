@@ -0,0 +1,51 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// Parameter kinds, used by ParameterData.Kind to say where a Parameter is
+// carried on the wire.
+const (
+	PathParameterKind = iota
+	QueryParameterKind
+	HeaderParameterKind
+	FormParameterKind
+	BodyParameterKind
+)
+
+// ParameterData holds the fields of a Parameter. It is returned (rather
+// than exposing the fields directly) so consumers such as the openapi
+// subpackage read it through a stable accessor instead of the struct's
+// internals.
+type ParameterData struct {
+	Name, Description string
+	Kind              int
+	Required          bool
+}
+
+// Parameter documents a single path, query, header, form or body parameter
+// accepted by a Route, surfaced through Route.ParameterDocs.
+type Parameter struct {
+	data *ParameterData
+}
+
+// Data returns the fields backing this Parameter.
+func (p *Parameter) Data() *ParameterData {
+	return p.data
+}
+
+// NewPathParameter creates a required path Parameter.
+func NewPathParameter(name, description string) *Parameter {
+	return &Parameter{data: &ParameterData{Name: name, Description: description, Kind: PathParameterKind, Required: true}}
+}
+
+// NewQueryParameter creates an optional query Parameter.
+func NewQueryParameter(name, description string) *Parameter {
+	return &Parameter{data: &ParameterData{Name: name, Description: description, Kind: QueryParameterKind}}
+}
+
+// NewHeaderParameter creates an optional header Parameter.
+func NewHeaderParameter(name, description string) *Parameter {
+	return &Parameter{data: &ParameterData{Name: name, Description: description, Kind: HeaderParameterKind}}
+}
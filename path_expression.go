@@ -0,0 +1,13 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// pathExpression is a vestigial cache slot on Route for a possible future
+// regex-compiled representation of relativePath. Route matching today goes
+// entirely through the pluggable PathProcessor (see path_processor.go), so
+// nothing populates this field yet.
+type pathExpression struct {
+	Source string
+}
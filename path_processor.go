@@ -0,0 +1,181 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PathProcessor tokenizes a Route's Path, matches an incoming request URL
+// against it, and extracts the path parameter values found there. A
+// Container holds one and assigns it to every Route it builds, so callers
+// who need a different parameter syntax (e.g. ":name" instead of "{name}")
+// can plug in their own implementation without forking the router.
+type PathProcessor interface {
+	// Tokenize splits path into its segments, in the same form used by
+	// both a Route.Path and an incoming request URL path.
+	Tokenize(path string) []string
+	// Matches reports whether urlPath satisfies route's Path, including
+	// any per-segment constraint the processor's syntax supports (e.g. a
+	// curlyPathProcessor regex constraint or tail wildcard).
+	Matches(route *Route, urlPath string) bool
+	// ExtractParameters returns the path parameter values found in urlPath
+	// for route, keyed by parameter name. Only meaningful when Matches
+	// already reported true.
+	ExtractParameters(route *Route, urlPath string) map[string]string
+}
+
+// defaultPathProcessor is used by Route.postBuild when a Container has not
+// assigned one, preserving the historical "{name}" segment-by-segment behavior.
+var defaultPathProcessor PathProcessor = regexPathProcessor{}
+
+// RegexPathProcessor is the default "{name}" parameter syntax, exported so
+// a Container can be switched back to it explicitly via SetPathProcessor.
+var RegexPathProcessor PathProcessor = regexPathProcessor{}
+
+// CurlyPathProcessor additionally supports "{name:pattern}" regex
+// constraints and "{name:*}" tail wildcards; assign it to a Container via
+// SetPathProcessor to opt in.
+var CurlyPathProcessor PathProcessor = curlyPathProcessor{}
+
+// regexPathProcessor implements the original "{name}" parameter syntax,
+// matched positionally, token by token, against route.pathParts.
+type regexPathProcessor struct{}
+
+func (p regexPathProcessor) Tokenize(path string) []string {
+	return tokenizePath(path)
+}
+
+// Matches requires the same number of segments as route.pathParts, with a
+// "{name}" segment matching any single non-empty token and every other
+// segment matching literally.
+func (p regexPathProcessor) Matches(route *Route, urlPath string) bool {
+	urlParts := tokenizePath(urlPath)
+	if last := len(urlParts) - 1; last >= 0 {
+		urlParts[last], _ = splitVerb(urlParts[last])
+	}
+	if len(urlParts) != len(route.pathParts) {
+		return false
+	}
+	for i, key := range route.pathParts {
+		if strings.HasPrefix(key, "{") {
+			continue
+		}
+		if key != urlParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p regexPathProcessor) ExtractParameters(route *Route, urlPath string) map[string]string {
+	urlParts := tokenizePath(urlPath)
+	if last := len(urlParts) - 1; last >= 0 {
+		urlParts[last], _ = splitVerb(urlParts[last])
+	}
+	pathParameters := map[string]string{}
+	for i, key := range route.pathParts {
+		var value string
+		if i >= len(urlParts) {
+			value = ""
+		} else {
+			value = urlParts[i]
+		}
+		if strings.HasPrefix(key, "{") { // path-parameter
+			pathParameters[strings.Trim(key, "{}")] = value
+		}
+	}
+	return pathParameters
+}
+
+// curlyPathProcessor is a processor that additionally supports a regex
+// constraint on a parameter, e.g. "{id:[0-9]+}", and a tail-match wildcard,
+// e.g. "{path:*}" to capture the remainder of the URL for routes such as
+// "/files/{path:*}". It walks each route's segments linearly; it builds no
+// trie, and it compiles a constraint's regex on every Matches call.
+type curlyPathProcessor struct{}
+
+func (p curlyPathProcessor) Tokenize(path string) []string {
+	return tokenizePath(path)
+}
+
+// Matches walks route.pathParts against urlPath segment by segment: a
+// literal segment must match exactly, a "{name}" segment matches any single
+// token, a "{name:pattern}" segment additionally requires the token to
+// match ^pattern$, and a "{name:*}" segment matches (and stops the walk at)
+// every remaining token, including zero of them.
+func (p curlyPathProcessor) Matches(route *Route, urlPath string) bool {
+	urlParts := tokenizePath(urlPath)
+	if last := len(urlParts) - 1; last >= 0 {
+		urlParts[last], _ = splitVerb(urlParts[last])
+	}
+	for i, part := range route.pathParts {
+		name, constraint, isParam := curlyToken(part)
+		_ = name
+		if isParam && constraint == "*" {
+			return true
+		}
+		if i >= len(urlParts) {
+			return false
+		}
+		if !isParam {
+			if part != urlParts[i] {
+				return false
+			}
+			continue
+		}
+		if constraint != "" {
+			re, err := regexp.Compile("^(?:" + constraint + ")$")
+			if err != nil || !re.MatchString(urlParts[i]) {
+				return false
+			}
+		}
+	}
+	return len(urlParts) == len(route.pathParts)
+}
+
+func (p curlyPathProcessor) ExtractParameters(route *Route, urlPath string) map[string]string {
+	urlParts := tokenizePath(urlPath)
+	if last := len(urlParts) - 1; last >= 0 {
+		urlParts[last], _ = splitVerb(urlParts[last])
+	}
+	pathParameters := map[string]string{}
+	for i, part := range route.pathParts {
+		name, constraint, isParam := curlyToken(part)
+		if !isParam {
+			continue
+		}
+		if constraint == "*" {
+			// tail wildcard: capture the remainder of the URL, slash-joined
+			if i < len(urlParts) {
+				pathParameters[name] = strings.Join(urlParts[i:], "/")
+			} else {
+				pathParameters[name] = ""
+			}
+			break
+		}
+		if i < len(urlParts) {
+			pathParameters[name] = urlParts[i]
+		} else {
+			pathParameters[name] = ""
+		}
+	}
+	return pathParameters
+}
+
+// curlyToken parses a path segment such as "{name}" or "{name:[a-z]+}" into
+// its parameter name and optional regex (or "*" wildcard) constraint.
+// isParam is false for plain literal segments.
+func curlyToken(segment string) (name string, constraint string, isParam bool) {
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+		return "", "", false
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+	if idx := strings.Index(inner, ":"); idx != -1 {
+		return inner[:idx], inner[idx+1:], true
+	}
+	return inner, "", true
+}
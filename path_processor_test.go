@@ -0,0 +1,72 @@
+package restful
+
+import "testing"
+
+func TestCurlyPathProcessorTailWildcard(t *testing.T) {
+	container := NewContainer()
+	container.SetPathProcessor(CurlyPathProcessor)
+	ws := NewWebService("/files")
+	ws.AddRoute(Route{Method: "GET", Path: "/files/{path:*}"})
+	container.Add(ws)
+	route := &ws.routes[0]
+
+	if !route.matches("/files/a/b/c.txt") {
+		t.Fatal("tail wildcard should match a deep path")
+	}
+	if !route.matches("/files") {
+		t.Fatal("tail wildcard should match zero remaining segments")
+	}
+	params := route.extractParameters("/files/a/b/c.txt")
+	if params["path"] != "a/b/c.txt" {
+		t.Errorf(`params["path"] = %q, want "a/b/c.txt"`, params["path"])
+	}
+}
+
+func TestCurlyPathProcessorRegexConstraint(t *testing.T) {
+	container := NewContainer()
+	container.SetPathProcessor(CurlyPathProcessor)
+	ws := NewWebService("/items")
+	ws.AddRoute(Route{Method: "GET", Path: "/items/{id:[0-9]+}"})
+	container.Add(ws)
+	route := &ws.routes[0]
+
+	if !route.matches("/items/42") {
+		t.Error("numeric id should satisfy the [0-9]+ constraint")
+	}
+	if route.matches("/items/abc") {
+		t.Error("non-numeric id should violate the [0-9]+ constraint and not match")
+	}
+}
+
+func TestCurlyPathProcessorLetsDistinctConstraintsCoexist(t *testing.T) {
+	container := NewContainer()
+	container.SetPathProcessor(CurlyPathProcessor)
+	ws := NewWebService("/items")
+	ws.AddRoute(Route{Method: "GET", Path: "/items/{id:[0-9]+}"})
+	ws.AddRoute(Route{Method: "GET", Path: "/items/{name:[a-z]+}"})
+	container.Add(ws)
+	numeric := &ws.routes[0]
+	alpha := &ws.routes[1]
+
+	if !numeric.matches("/items/42") || alpha.matches("/items/42") {
+		t.Error("/items/42 should match only the numeric-constrained route")
+	}
+	if !alpha.matches("/items/abc") || numeric.matches("/items/abc") {
+		t.Error("/items/abc should match only the alpha-constrained route")
+	}
+}
+
+func TestRegexPathProcessorExactSegmentCount(t *testing.T) {
+	container := NewContainer() // defaults to RegexPathProcessor
+	ws := NewWebService("/users")
+	ws.AddRoute(Route{Method: "GET", Path: "/users/{id}"})
+	container.Add(ws)
+	route := &ws.routes[0]
+
+	if !route.matches("/users/42") {
+		t.Error("should match a single path parameter segment")
+	}
+	if route.matches("/users/42/posts") {
+		t.Error("regex processor requires an exact segment count")
+	}
+}
@@ -0,0 +1,67 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Request is a thin wrapper around *http.Request that also carries the
+// path parameters and custom verb resolved by the matched Route (see the
+// ":verb" suffix supported on Route.Path), plus the request body cached by
+// the implicit body-validation filter, if it ran.
+type Request struct {
+	Request        *http.Request
+	pathParameters map[string]string
+	selectedVerb   string
+
+	// cachedEntityBytes/cachedEntityRead let a filter that has already
+	// read Request.Body (such as the body-validation filter installed
+	// by Route.Validate(true)) hand those bytes to ReadEntity instead of
+	// it reading the body a second time.
+	cachedEntityBytes []byte
+	cachedEntityRead  bool
+}
+
+func newRequest(httpRequest *http.Request) *Request {
+	return &Request{Request: httpRequest}
+}
+
+// PathParameter returns the path parameter value for name, or "" if the
+// matched route did not declare one by that name.
+func (r *Request) PathParameter(name string) string {
+	return r.pathParameters[name]
+}
+
+// SelectedVerb returns the custom verb (AIP-136 style) matched off the
+// route's path, e.g. "activate" for "/users/{id}:activate", or "" when the
+// matched route has none.
+func (r *Request) SelectedVerb() string {
+	return r.selectedVerb
+}
+
+// ReadEntity unmarshals the request body into value, honoring the
+// Content-Type header (application/json or */yaml). When a validation
+// filter already read and cached the body, that cached copy is reused
+// rather than reading Request.Body again.
+func (r *Request) ReadEntity(value interface{}) error {
+	body := r.cachedEntityBytes
+	if !r.cachedEntityRead {
+		data, err := ioutil.ReadAll(r.Request.Body)
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+	if strings.Contains(r.Request.Header.Get("Content-Type"), "yaml") {
+		return yaml.Unmarshal(body, value)
+	}
+	return json.Unmarshal(body, value)
+}
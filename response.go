@@ -0,0 +1,46 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Response wraps http.ResponseWriter, additionally recording the Accept
+// header of the request it answers and the Produces list of the Route
+// handling it. The write helpers below do not consult these fields;
+// WriteAsJson always writes application/json.
+type Response struct {
+	http.ResponseWriter
+	requestAccept string
+	routeProduces []string
+}
+
+func newResponse(httpWriter http.ResponseWriter) *Response {
+	return &Response{ResponseWriter: httpWriter}
+}
+
+// WriteAsJson writes value as a 200 response body with Content-Type
+// application/json.
+func (r *Response) WriteAsJson(value interface{}) error {
+	return r.WriteHeaderAndJson(http.StatusOK, value, "application/json")
+}
+
+// WriteHeaderAndJson sets contentType, writes status, and encodes value as
+// the JSON response body.
+func (r *Response) WriteHeaderAndJson(status int, value interface{}, contentType string) error {
+	r.Header().Set("Content-Type", contentType)
+	r.WriteHeader(status)
+	return json.NewEncoder(r).Encode(value)
+}
+
+// WriteErrorString writes status with message as a plain-text body.
+func (r *Response) WriteErrorString(status int, message string) error {
+	r.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	r.WriteHeader(status)
+	_, err := r.Write([]byte(message))
+	return err
+}
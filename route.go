@@ -6,6 +6,7 @@ package restful
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -26,6 +27,28 @@ type Route struct {
 	pathParts    []string
 	pathExpr     *pathExpression // cached compilation of relativePath as RegExp
 
+	// verb is the custom verb (AIP-136 style) parsed off the last path
+	// segment, e.g. "activate" for a Path of "/users/{id}:activate".
+	// It is empty for routes without one.
+	verb string
+
+	// processor tokenizes Path and extracts path parameters; it defaults to
+	// the Container's configured PathProcessor, falling back to
+	// regexPathProcessor when the route was built without a Container.
+	processor PathProcessor
+
+	// CORS, when set, overrides the container-level CORS filter for this
+	// route: postBuild prepends a filter that applies it to every
+	// invocation, and the container consults handlePreflight for OPTIONS
+	// requests before falling back to its own default policy.
+	CORS *CORSPolicy
+
+	// validateBody is set by Validate(true); when true, postBuild reflects
+	// ReadSample into bodySchema and prepends a filter that rejects
+	// non-conforming request bodies before Function runs.
+	validateBody bool
+	bodySchema   *bodySchema
+
 	// documentation
 	Doc                     string
 	Operation               string
@@ -35,7 +58,34 @@ type Route struct {
 
 // Initialize for Route
 func (r *Route) postBuild() {
-	r.pathParts = tokenizePath(r.Path)
+	if r.processor == nil {
+		r.processor = defaultPathProcessor
+	}
+	r.pathParts = r.processor.Tokenize(r.Path)
+	if last := len(r.pathParts) - 1; last >= 0 {
+		r.pathParts[last], r.verb = splitVerb(r.pathParts[last])
+	}
+	// Validation is prepended before CORS so that CORS ends up outermost:
+	// its headers must be set even on requests a later filter rejects (e.g.
+	// a 400 from failed body validation), or a browser will treat the
+	// rejection as a CORS failure and hide it from the calling JS code.
+	if r.validateBody && r.ReadSample != nil {
+		r.bodySchema = buildBodySchema(r.ReadSample)
+		r.Filters = append([]FilterFunction{r.bodySchema.validationFilter}, r.Filters...)
+	}
+	if r.CORS != nil {
+		r.Filters = append([]FilterFunction{r.CORS.filter}, r.Filters...)
+	}
+}
+
+// Validate enables (or disables) request-body validation against a JSON
+// Schema reflected from ReadSample. It must be called before the route is
+// passed to WebService.AddRoute, which stores a copy; the schema is then
+// compiled once in postBuild when Container.Add builds the route. It
+// returns r so calls can be chained.
+func (r *Route) Validate(enabled bool) *Route {
+	r.validateBody = enabled
+	return r
 }
 
 // Create Request and Response from their http versions
@@ -43,6 +93,7 @@ func (r *Route) wrapRequestResponse(httpWriter http.ResponseWriter, httpRequest
 	params := r.extractParameters(httpRequest.URL.Path)
 	wrappedRequest := newRequest(httpRequest)
 	wrappedRequest.pathParameters = params
+	wrappedRequest.selectedVerb = r.verb
 	wrappedResponse := newResponse(httpWriter)
 	wrappedResponse.requestAccept = httpRequest.Header.Get(HEADER_Accept)
 	wrappedResponse.routeProduces = r.Produces
@@ -60,67 +111,132 @@ func (r *Route) dispatchWithFilters(wrappedRequest *Request, wrappedResponse *Re
 	}
 }
 
-// Return whether the mimeType matches to what this Route can produce.
-func (r Route) matchesAccept(mimeTypesWithQuality string) bool {
-	parts := strings.Split(mimeTypesWithQuality, ",")
-	for _, each := range parts {
-		var withoutQuality string
-		if strings.Contains(each, ";") {
-			withoutQuality = strings.Split(each, ";")[0]
-		} else {
-			withoutQuality = each
-		}
-		// trim before compare
-		withoutQuality = strings.Trim(withoutQuality, " ")
-		if withoutQuality == "*/*" {
-			return true
+// mediaRange is a single entry of a parsed Accept or Content-Type header,
+// e.g. "application/xml;q=0.9" becomes {type: "application", subtype: "xml", q: 0.9}.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseMediaRanges parses a comma-separated header of media ranges per
+// RFC 7231 §5.3, defaulting q to 1.0 when absent. Entries that fail to
+// parse a q value fall back to that default rather than being dropped.
+func parseMediaRanges(header string) []mediaRange {
+	parts := strings.Split(header, ",")
+	ranges := make([]mediaRange, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
 		}
-		for _, other := range r.Produces {
-			if other == withoutQuality {
-				return true
+		mediaType := part
+		q := 1.0
+		if idx := strings.Index(part, ";"); idx != -1 {
+			mediaType = strings.TrimSpace(part[:idx])
+			for _, param := range strings.Split(part[idx+1:], ";") {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = parsed
+				}
 			}
 		}
+		typ, subtype := mediaType, "*"
+		if idx := strings.Index(mediaType, "/"); idx != -1 {
+			typ, subtype = mediaType[:idx], mediaType[idx+1:]
+		}
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// matchScore scores a concrete MIME type such as "application/json" against
+// this media range per RFC 7231 §5.3: an exact match scores 1.0, a "type/*"
+// match scores 0.02 and a "*/*" match scores 0.01, each multiplied by the
+// range's q value. A score of 0 means no match at all.
+func (m mediaRange) matchScore(mimeType string) float64 {
+	typ, subtype := mimeType, "*"
+	if idx := strings.Index(mimeType, "/"); idx != -1 {
+		typ, subtype = mimeType[:idx], mimeType[idx+1:]
+	}
+	switch {
+	case m.typ == "*" && m.subtype == "*":
+		return 0.01 * m.q
+	case m.typ == typ && m.subtype == "*":
+		return 0.02 * m.q
+	case m.typ == typ && m.subtype == subtype:
+		return 1.0 * m.q
+	default:
+		return 0
 	}
-	return false
 }
 
-// Return whether the mimeType matches to what this Route can consume.
-func (r Route) matchesContentType(mimeTypes string) bool {
-	parts := strings.Split(mimeTypes, ",")
-	for _, each := range parts {
-		var contentType string
-		if strings.Contains(each, ";") {
-			contentType = strings.Split(each, ";")[0]
-		} else {
-			contentType = each
+// acceptScore scores this route's Produces list against an Accept header,
+// honoring client q-values per RFC 7231 §5.3, and returns the best score
+// found (0 when nothing matches). It replaces the former boolean
+// matchesAccept so the container can pick the highest-scoring route/produce
+// pair instead of whichever Produces entry happens to be declared first.
+func (r Route) acceptScore(acceptHeader string) float64 {
+	best := 0.0
+	for _, rng := range parseMediaRanges(acceptHeader) {
+		for _, produce := range r.Produces {
+			if score := rng.matchScore(produce); score > best {
+				best = score
+			}
 		}
-		// trim before compare
-		contentType = strings.Trim(contentType, " ")
-		for _, other := range r.Consumes {
-			if other == "*/*" || other == contentType {
-				return true
+	}
+	return best
+}
+
+// contentTypeScore scores this route's Consumes list against a Content-Type
+// header the same way acceptScore does, and replaces the former boolean
+// matchesContentType.
+func (r Route) contentTypeScore(contentTypeHeader string) float64 {
+	best := 0.0
+	for _, rng := range parseMediaRanges(contentTypeHeader) {
+		for _, consume := range r.Consumes {
+			if score := rng.matchScore(consume); score > best {
+				best = score
 			}
 		}
 	}
-	return false
+	return best
 }
 
 // Extract the parameters from the request url path
 func (r Route) extractParameters(urlPath string) map[string]string {
+	return r.processor.ExtractParameters(&r, urlPath)
+}
+
+// matches reports whether this route's Path (honoring its PathProcessor's
+// parameter syntax) and custom verb both match urlPath. It is what the
+// Container consults, across every candidate route, before narrowing by
+// HTTP method and Accept negotiation.
+func (r Route) matches(urlPath string) bool {
+	if !r.matchesVerb(urlPath) {
+		return false
+	}
+	processor := r.processor
+	if processor == nil {
+		processor = defaultPathProcessor
+	}
+	return processor.Matches(&r, urlPath)
+}
+
+// matchesVerb reports whether urlPath carries the same custom verb (if any)
+// as this route, so that e.g. "/users/{id}" and "/users/{id}:activate" are
+// matched as distinct routes rather than the verb being absorbed as part of
+// a path parameter. It is consulted by the router alongside the regular
+// path matching.
+func (r Route) matchesVerb(urlPath string) bool {
 	urlParts := tokenizePath(urlPath)
-	pathParameters := map[string]string{}
-	for i, key := range r.pathParts {
-		var value string
-		if i >= len(urlParts) {
-			value = ""
-		} else {
-			value = urlParts[i]
-		}
-		if strings.HasPrefix(key, "{") { // path-parameter
-			pathParameters[strings.Trim(key, "{}")] = value
-		}
+	if len(urlParts) == 0 {
+		return r.verb == ""
 	}
-	return pathParameters
+	_, verb := splitVerb(urlParts[len(urlParts)-1])
+	return verb == r.verb
 }
 
 // Tokenize an URL path using the slash separator ; the result does not have empty tokens
@@ -131,6 +247,22 @@ func tokenizePath(path string) []string {
 	return strings.Split(strings.Trim(path, "/"), "/")
 }
 
+// splitVerb splits a trailing custom verb off the last path segment, e.g.
+// "{id}:activate" becomes ("{id}", "activate"). A colon that falls inside an
+// unterminated "{" parameter, such as the constraint in "{name:[a-z]+}", is
+// not treated as a verb separator. Segments without a verb are returned
+// unchanged with an empty verb.
+func splitVerb(segment string) (path string, verb string) {
+	idx := strings.LastIndex(segment, ":")
+	if idx == -1 {
+		return segment, ""
+	}
+	if strings.HasPrefix(segment, "{") && !strings.Contains(segment[:idx], "}") {
+		return segment, ""
+	}
+	return segment[:idx], segment[idx+1:]
+}
+
 // for debugging
 func (r Route) String() string {
 	return r.Method + " " + r.Path
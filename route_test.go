@@ -0,0 +1,84 @@
+package restful
+
+import "testing"
+
+func TestSplitVerb(t *testing.T) {
+	cases := []struct {
+		segment, wantPath, wantVerb string
+	}{
+		{"{id}:activate", "{id}", "activate"},
+		{"files:batchDelete", "files", "batchDelete"},
+		{"{id}", "{id}", ""},
+		{"{name:[a-z]+}", "{name:[a-z]+}", ""}, // regex constraint, not a verb
+		{"plain", "plain", ""},
+	}
+	for _, c := range cases {
+		path, verb := splitVerb(c.segment)
+		if path != c.wantPath || verb != c.wantVerb {
+			t.Errorf("splitVerb(%q) = (%q, %q), want (%q, %q)", c.segment, path, verb, c.wantPath, c.wantVerb)
+		}
+	}
+}
+
+func TestRouteMatchesDistinguishesVerb(t *testing.T) {
+	container := NewContainer()
+	ws := NewWebService("/users")
+	plain := Route{Method: "GET", Path: "/users/{id}"}
+	activate := Route{Method: "POST", Path: "/users/{id}:activate"}
+	ws.AddRoute(plain)
+	ws.AddRoute(activate)
+	container.Add(ws)
+
+	plainRoute := &ws.routes[0]
+	activateRoute := &ws.routes[1]
+
+	if !plainRoute.matches("/users/42") {
+		t.Error("plain route should match /users/42")
+	}
+	if plainRoute.matches("/users/42:activate") {
+		t.Error("plain route should not match a URL carrying a custom verb")
+	}
+	if !activateRoute.matches("/users/42:activate") {
+		t.Error("verb route should match /users/42:activate")
+	}
+	if activateRoute.matches("/users/42") {
+		t.Error("verb route should not match a URL without its verb")
+	}
+}
+
+func TestAcceptScore(t *testing.T) {
+	cases := []struct {
+		name     string
+		produces []string
+		accept   string
+		want     float64
+	}{
+		{"exact match wins over wildcard", []string{"application/json"}, "application/json", 1.0},
+		{"type wildcard scores low", []string{"application/json"}, "application/*", 0.02},
+		{"any wildcard scores lowest", []string{"application/json"}, "*/*", 0.01},
+		{"q-value multiplies score", []string{"application/xml"}, "application/xml;q=0.9", 0.9},
+		{"no match scores zero", []string{"application/json"}, "text/plain", 0},
+		{"best of several ranges wins", []string{"application/xml"}, "application/json;q=0.9, application/xml;q=0.1", 0.1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := Route{Produces: c.produces}
+			if got := r.acceptScore(c.accept); got != c.want {
+				t.Errorf("acceptScore(%q) = %v, want %v", c.accept, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSelectBestRoutePrefersHigherQualityOverDeclarationOrder(t *testing.T) {
+	jsonRoute := &Route{Method: "GET", Produces: []string{"application/json"}}
+	xmlRoute := &Route{Method: "GET", Produces: []string{"application/xml"}}
+
+	best, score := selectBestRoute([]*Route{jsonRoute, xmlRoute}, "application/xml;q=0.9, application/json;q=0.1")
+	if best != xmlRoute {
+		t.Fatalf("expected the higher-q XML route to win even though JSON was declared first")
+	}
+	if score != 0.9 {
+		t.Errorf("score = %v, want 0.9", score)
+	}
+}
@@ -0,0 +1,225 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// bodySchema is a JSON-Schema-like description of a ReadSample struct,
+// reflected once at postBuild time by Route.Validate(true) and then used by
+// the validation filter it installs to check every request body before the
+// handler runs.
+type bodySchema struct {
+	fields []fieldSchema
+}
+
+// fieldSchema is the set of constraints derived from a single ReadSample
+// struct field: required (non-pointer, no `json:",omitempty"`), `enum:`,
+// `min:`/`max:`, and `pattern:` tags.
+type fieldSchema struct {
+	name     string
+	required bool
+	enum     []string
+	min, max *float64
+	pattern  *regexp.Regexp
+}
+
+// buildBodySchema reflects sample, normally a Route's ReadSample, into a
+// bodySchema.
+func buildBodySchema(sample interface{}) *bodySchema {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return &bodySchema{}
+	}
+	return &bodySchema{fields: structFieldSchemas(t)}
+}
+
+// structFieldSchemas reflects t's fields into fieldSchemas, promoting the
+// fields of an anonymous (embedded) struct field inline rather than
+// validating the embedded field itself as a single value — e.g. embedding a
+// shared AuditFields struct validates its promoted createdBy field
+// directly, the same promotion openapi.schemaBuilder.structSchema performs
+// for the OpenAPI schema of the same ReadSample.
+func structFieldSchemas(t reflect.Type) []fieldSchema {
+	var fields []fieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		// An anonymous field's own PkgPath reflects the exportedness of its
+		// *type name*, not of the fields it promotes, so it's handled
+		// before the unexported-field skip below.
+		if field.Anonymous && tag == "" {
+			fields = append(fields, structFieldSchemas(dereference(field.Type))...)
+			continue
+		}
+
+		if field.PkgPath != "" { // unexported, non-anonymous
+			continue
+		}
+		name, omitempty := jsonFieldName(field)
+		fs := fieldSchema{name: name, required: field.Type.Kind() != reflect.Ptr && !omitempty}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			fs.enum = strings.Split(enum, ",")
+		}
+		if min := field.Tag.Get("min"); min != "" {
+			if v, err := strconv.ParseFloat(min, 64); err == nil {
+				fs.min = &v
+			}
+		}
+		if max := field.Tag.Get("max"); max != "" {
+			if v, err := strconv.ParseFloat(max, 64); err == nil {
+				fs.max = &v
+			}
+		}
+		if pattern := field.Tag.Get("pattern"); pattern != "" {
+			if re, err := regexp.Compile(pattern); err == nil {
+				fs.pattern = re
+			}
+		}
+		fields = append(fields, fs)
+	}
+	return fields
+}
+
+func dereference(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// validationError describes a single constraint violated by a request
+// body, reported back to the client as part of a 400 response.
+type validationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validate checks data, the JSON-decoded request body, against schema and
+// returns every violation found.
+func (schema *bodySchema) validate(data map[string]interface{}) []validationError {
+	var errs []validationError
+	for _, field := range schema.fields {
+		value, present := data[field.name]
+		if !present || value == nil {
+			if field.required {
+				errs = append(errs, validationError{Field: field.name, Message: "is required"})
+			}
+			continue
+		}
+		if len(field.enum) > 0 {
+			if str, ok := value.(string); !ok || !contains(field.enum, str) {
+				errs = append(errs, validationError{Field: field.name, Message: fmt.Sprintf("must be one of %s", strings.Join(field.enum, ","))})
+			}
+		}
+		if num, ok := numericValue(value); ok {
+			if field.min != nil && num < *field.min {
+				errs = append(errs, validationError{Field: field.name, Message: fmt.Sprintf("must be >= %v", *field.min)})
+			}
+			if field.max != nil && num > *field.max {
+				errs = append(errs, validationError{Field: field.name, Message: fmt.Sprintf("must be <= %v", *field.max)})
+			}
+		}
+		if field.pattern != nil {
+			if str, ok := value.(string); ok && !field.pattern.MatchString(str) {
+				errs = append(errs, validationError{Field: field.name, Message: "does not match pattern " + field.pattern.String()})
+			}
+		}
+	}
+	return errs
+}
+
+// numericValue normalizes a decoded JSON or YAML number to float64: the
+// encoding/json decoder always produces float64, but yaml.v2 produces int
+// for integer literals, so min/max checks must accept both.
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// validationFilter is prepended to a Route's Filters by postBuild when
+// Route.Validate(true) was set and ReadSample is non-nil. For JSON or YAML
+// Consumes types it reads and caches the request body, validates it against
+// schema, and on failure writes a 400 with the violated constraints instead
+// of invoking the handler. It also resets http.Request.Body to a fresh
+// reader over the same bytes, and Request.ReadEntity reuses the cached body
+// rather than reading http.Request.Body again.
+func (schema *bodySchema) validationFilter(req *Request, resp *Response, chain *FilterChain) {
+	contentType := req.Request.Header.Get("Content-Type")
+	isYAML := strings.Contains(contentType, "yaml")
+	if !isYAML && !strings.Contains(contentType, "json") {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		resp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.cachedEntityBytes = body
+	req.cachedEntityRead = true
+
+	var data map[string]interface{}
+	var decodeErr error
+	if isYAML {
+		decodeErr = yaml.Unmarshal(body, &data)
+	} else {
+		decodeErr = json.Unmarshal(body, &data)
+	}
+	if decodeErr != nil {
+		resp.WriteErrorString(http.StatusBadRequest, "invalid request body: "+decodeErr.Error())
+		return
+	}
+	if violations := schema.validate(data); len(violations) > 0 {
+		resp.WriteHeaderAndJson(http.StatusBadRequest, violations, "application/json")
+		return
+	}
+	chain.ProcessFilter(req, resp)
+}
@@ -0,0 +1,145 @@
+package restful
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+type widget struct {
+	Name  string `json:"name"`
+	Size  int    `json:"size"`
+	Color string `json:"color" enum:"red,green,blue"`
+	Note  string `json:"note,omitempty"`
+}
+
+func widgetSchema() *bodySchema {
+	schema := buildBodySchema(widget{})
+	for i := range schema.fields {
+		if schema.fields[i].name == "size" {
+			min, max := 1.0, 10.0
+			schema.fields[i].min = &min
+			schema.fields[i].max = &max
+		}
+	}
+	return schema
+}
+
+func TestBodySchemaValidate(t *testing.T) {
+	schema := widgetSchema()
+
+	cases := []struct {
+		name       string
+		data       map[string]interface{}
+		wantFields []string
+	}{
+		{"valid", map[string]interface{}{"name": "gizmo", "size": 5.0, "color": "red"}, nil},
+		{"missing required field", map[string]interface{}{"size": 5.0, "color": "red"}, []string{"name"}},
+		{"omitempty field is not required", map[string]interface{}{"name": "gizmo", "size": 5.0, "color": "red"}, nil},
+		{"enum violation", map[string]interface{}{"name": "gizmo", "size": 5.0, "color": "purple"}, []string{"color"}},
+		{"below min", map[string]interface{}{"name": "gizmo", "size": 0.0, "color": "red"}, []string{"size"}},
+		{"above max", map[string]interface{}{"name": "gizmo", "size": 11.0, "color": "red"}, []string{"size"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			violations := schema.validate(c.data)
+			if len(violations) != len(c.wantFields) {
+				t.Fatalf("got %d violations (%v), want %d", len(violations), violations, len(c.wantFields))
+			}
+			for i, field := range c.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("violation[%d].Field = %q, want %q", i, violations[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+type auditFields struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type widgetWithEmbed struct {
+	auditFields
+	Name string `json:"name"`
+}
+
+func TestBuildBodySchemaPromotesEmbeddedFields(t *testing.T) {
+	schema := buildBodySchema(widgetWithEmbed{})
+
+	byName := map[string]fieldSchema{}
+	for _, f := range schema.fields {
+		byName[f.name] = f
+	}
+
+	if _, ok := byName["auditFields"]; ok {
+		t.Error("the embedded struct itself must not appear as a field named after its type")
+	}
+	createdBy, ok := byName["createdBy"]
+	if !ok {
+		t.Fatalf("expected the promoted createdBy field, got %+v", schema.fields)
+	}
+	if !createdBy.required {
+		t.Error("promoted createdBy has no omitempty and should be required")
+	}
+	if !byName["name"].required {
+		t.Error("name has no omitempty and should be required")
+	}
+
+	if v := schema.validate(map[string]interface{}{"name": "gizmo", "createdBy": "alice"}); len(v) != 0 {
+		t.Errorf("expected a body with the promoted field present to pass, got %v", v)
+	}
+	if v := schema.validate(map[string]interface{}{"name": "gizmo"}); len(v) != 1 || v[0].Field != "createdBy" {
+		t.Errorf("expected a missing promoted createdBy to be reported, got %v", v)
+	}
+}
+
+func TestBodySchemaValidatePattern(t *testing.T) {
+	type coded struct {
+		SKU string `json:"sku" pattern:"^[A-Z]{3}-[0-9]{4}$"`
+	}
+	schema := buildBodySchema(coded{})
+
+	if v := schema.validate(map[string]interface{}{"sku": "ABC-1234"}); len(v) != 0 {
+		t.Errorf("expected a matching SKU to pass, got %v", v)
+	}
+	if v := schema.validate(map[string]interface{}{"sku": "abc123"}); len(v) != 1 {
+		t.Errorf("expected a non-matching SKU to fail, got %v", v)
+	}
+}
+
+func TestValidationFilterRejectsInvalidJSONBody(t *testing.T) {
+	schema := widgetSchema()
+	var handlerCalled bool
+	chain := &FilterChain{Target: func(req *Request, resp *Response) { handlerCalled = true }}
+
+	httpReq := httptest.NewRequest("POST", "/widgets", bytes.NewBufferString(`{"size": 5, "color": "red"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	schema.validationFilter(newRequest(httpReq), newResponse(recorder), chain)
+
+	if handlerCalled {
+		t.Fatal("handler must not run when the body fails validation")
+	}
+	if recorder.Code != 400 {
+		t.Errorf("status = %d, want 400", recorder.Code)
+	}
+}
+
+func TestValidationFilterAcceptsYAMLConsumes(t *testing.T) {
+	schema := widgetSchema()
+	var handlerCalled bool
+	chain := &FilterChain{Target: func(req *Request, resp *Response) { handlerCalled = true }}
+
+	body := "name: gizmo\nsize: 5\ncolor: red\n"
+	httpReq := httptest.NewRequest("POST", "/widgets", bytes.NewBufferString(body))
+	httpReq.Header.Set("Content-Type", "application/yaml")
+	recorder := httptest.NewRecorder()
+
+	schema.validationFilter(newRequest(httpReq), newResponse(recorder), chain)
+
+	if !handlerCalled {
+		t.Fatalf("expected a valid YAML body to pass validation, got status %d", recorder.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package restful
+
+// Copyright 2013 Ernest Micklei. All rights reserved.
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+import "strings"
+
+// WebService groups a set of Routes under a common root path, along with
+// Consumes/Produces lists. AddRoute does not copy these lists onto the
+// Routes it registers; dispatch consults each Route's own Consumes/Produces.
+type WebService struct {
+	rootPath string
+	routes   []Route
+	Consumes []string
+	Produces []string
+}
+
+// NewWebService creates a WebService rooted at rootPath.
+func NewWebService(rootPath string) *WebService {
+	return &WebService{rootPath: rootPath}
+}
+
+// RootPath returns the root path this WebService's Routes are relative to.
+func (w *WebService) RootPath() string {
+	return w.rootPath
+}
+
+// Routes returns the Routes registered on this WebService, in the order
+// they were added.
+func (w *WebService) Routes() []Route {
+	return w.routes
+}
+
+// AddRoute registers route under this WebService, prefixing route.Path with
+// RootPath() if it is not already there. The Container a WebService is
+// later Add-ed to is responsible for assigning each Route's PathProcessor
+// and calling postBuild.
+func (w *WebService) AddRoute(route Route) *WebService {
+	if !strings.HasPrefix(route.Path, w.rootPath) {
+		route.Path = strings.TrimSuffix(w.rootPath, "/") + "/" + strings.TrimPrefix(route.Path, "/")
+	}
+	w.routes = append(w.routes, route)
+	return w
+}